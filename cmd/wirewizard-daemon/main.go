@@ -0,0 +1,25 @@
+// Command wirewizard-daemon is the privileged helper that holds
+// CAP_NET_ADMIN and brokers tunnel operations for unprivileged frontends
+// over ipc.SocketPath.
+package main
+
+import (
+	"log"
+
+	"wirewizard/ipc"
+	"wirewizard/store"
+)
+
+func main() {
+	sealer, err := store.DefaultSealer()
+	if err != nil {
+		log.Fatalf("wirewizard-daemon: setting up tunnel store: %v", err)
+	}
+
+	server := ipc.NewServer(store.New(sealer))
+
+	log.Printf("wirewizard-daemon: listening on %s", ipc.SocketPath)
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalf("wirewizard-daemon: %v", err)
+	}
+}
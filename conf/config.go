@@ -0,0 +1,140 @@
+// Package conf implements a structured reader/writer for wg-quick style
+// tunnel configuration files, modeled on wireguard-windows' conf package.
+package conf
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// IPCidr is an IP address together with its prefix length, as it appears in
+// an Address or AllowedIPs line.
+type IPCidr struct {
+	IP   net.IP
+	Cidr uint8
+}
+
+func (c IPCidr) String() string {
+	return fmt.Sprintf("%s/%d", c.IP.String(), c.Cidr)
+}
+
+// ParseIPCidr parses "ip" or "ip/cidr" into an IPCidr, defaulting the prefix
+// length to the full address width when no CIDR suffix is given.
+func ParseIPCidr(s string) (IPCidr, error) {
+	s = strings.TrimSpace(s)
+	host, bits, ok := strings.Cut(s, "/")
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return IPCidr{}, fmt.Errorf("%q is not a valid IP address", host)
+	}
+
+	maxBits := uint8(32)
+	if ip.To4() == nil {
+		maxBits = 128
+	}
+
+	if !ok {
+		return IPCidr{IP: ip, Cidr: maxBits}, nil
+	}
+
+	cidr, err := strconv.ParseUint(bits, 10, 8)
+	if err != nil || uint8(cidr) > maxBits {
+		return IPCidr{}, fmt.Errorf("%q is not a valid CIDR prefix", bits)
+	}
+
+	return IPCidr{IP: ip, Cidr: uint8(cidr)}, nil
+}
+
+// Endpoint is a peer endpoint. Host is kept as-is, unresolved, so that
+// hostnames which haven't answered a DNS query yet round-trip unchanged.
+type Endpoint struct {
+	Host string
+	Port uint16
+}
+
+func (e Endpoint) IsEmpty() bool {
+	return e.Host == ""
+}
+
+func (e Endpoint) String() string {
+	if e.IsEmpty() {
+		return ""
+	}
+	if strings.Contains(e.Host, ":") {
+		return fmt.Sprintf("[%s]:%d", e.Host, e.Port)
+	}
+	return fmt.Sprintf("%s:%d", e.Host, e.Port)
+}
+
+// ParseEndpoint parses "host:port", keeping host unresolved.
+func ParseEndpoint(s string) (Endpoint, error) {
+	host, portStr, err := net.SplitHostPort(strings.TrimSpace(s))
+	if err != nil {
+		return Endpoint{}, fmt.Errorf("%q is not a valid endpoint: %w", s, err)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return Endpoint{}, fmt.Errorf("%q is not a valid port", portStr)
+	}
+
+	return Endpoint{Host: strings.Trim(host, "[]"), Port: uint16(port)}, nil
+}
+
+// Obfuscation holds the AmneziaWG packet-header obfuscation parameters
+// (Jc/Jmin/Jmax junk packets, S1/S2 header sizes, H1..H4 header types). A
+// zero value means none of these keys were present in the config.
+type Obfuscation struct {
+	Jc   uint16
+	Jmin uint16
+	Jmax uint16
+	S1   uint16
+	S2   uint16
+	H1   uint32
+	H2   uint32
+	H3   uint32
+	H4   uint32
+}
+
+// IsZero reports whether none of the obfuscation keys were set.
+func (o Obfuscation) IsZero() bool {
+	return o == Obfuscation{}
+}
+
+// Interface holds the parsed contents of a config's [Interface] section.
+type Interface struct {
+	PrivateKey  wgtypes.Key
+	ListenPort  uint16
+	Addresses   []IPCidr
+	DNS         []string
+	MTU         uint16
+	Table       string
+	PreUp       string
+	PostUp      string
+	PreDown     string
+	PostDown    string
+	SaveConfig  bool
+	Obfuscation Obfuscation
+}
+
+// Peer holds the parsed contents of a single [Peer] section.
+type Peer struct {
+	PublicKey           wgtypes.Key
+	PresharedKey        wgtypes.Key
+	AllowedIPs          []IPCidr
+	Endpoint            Endpoint
+	PersistentKeepalive uint16
+}
+
+// Config is a fully parsed wg-quick tunnel configuration, supporting any
+// number of peers.
+type Config struct {
+	Name      string
+	Interface Interface
+	Peers     []Peer
+}
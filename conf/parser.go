@@ -0,0 +1,233 @@
+package conf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// ParseError reports the line and column of a malformed wg-quick config
+// line, so the caller can surface a precise error to the user.
+type ParseError struct {
+	Line, Column int
+	Message      string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+func errAt(line, column int, format string, args ...any) error {
+	return &ParseError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// FromWgQuick tokenizes and parses a wg-quick style config file into a
+// Config. Comments (# or ;), blank lines and leading/trailing whitespace are
+// ignored, and keys are matched case-insensitively.
+func FromWgQuick(data []byte, name string) (*Config, error) {
+	cfg := &Config{Name: name}
+
+	var section string
+	var peer Peer
+	inPeer := false
+	haveInterface := false
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNum := i + 1
+		line := raw
+
+		if idx := strings.IndexAny(line, "#;"); idx >= 0 {
+			line = line[:idx]
+		}
+		leading := len(line) - len(strings.TrimLeft(line, " \t"))
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		col1 := leading + 1
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, errAt(lineNum, col1, "unterminated section header %q", line)
+			}
+			if inPeer {
+				cfg.Peers = append(cfg.Peers, peer)
+				peer = Peer{}
+				inPeer = false
+			}
+			switch strings.ToLower(line) {
+			case "[interface]":
+				section = "interface"
+				haveInterface = true
+			case "[peer]":
+				section = "peer"
+				inPeer = true
+			default:
+				return nil, errAt(lineNum, col1, "unknown section %q", line)
+			}
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, errAt(lineNum, col1, "expected key = value, got %q", line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		value := strings.TrimSpace(line[eq+1:])
+		column := leading + eq + 2
+
+		var err error
+		switch section {
+		case "interface":
+			err = parseInterfaceKey(&cfg.Interface, key, value)
+		case "peer":
+			err = parsePeerKey(&peer, key, value)
+		default:
+			err = fmt.Errorf("key %q outside of any [Interface] or [Peer] section", key)
+		}
+		if err != nil {
+			return nil, errAt(lineNum, column, "%s", err)
+		}
+	}
+
+	if inPeer {
+		cfg.Peers = append(cfg.Peers, peer)
+	}
+	if !haveInterface {
+		return nil, fmt.Errorf("missing [Interface] section")
+	}
+
+	return cfg, nil
+}
+
+func parseInterfaceKey(iface *Interface, key, value string) error {
+	switch strings.ToLower(key) {
+	case "privatekey":
+		k, err := wgtypes.ParseKey(value)
+		if err != nil {
+			return fmt.Errorf("invalid PrivateKey: %w", err)
+		}
+		iface.PrivateKey = k
+	case "listenport":
+		port, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid ListenPort: %w", err)
+		}
+		iface.ListenPort = uint16(port)
+	case "address":
+		for _, part := range strings.Split(value, ",") {
+			addr, err := ParseIPCidr(part)
+			if err != nil {
+				return fmt.Errorf("invalid Address: %w", err)
+			}
+			iface.Addresses = append(iface.Addresses, addr)
+		}
+	case "dns":
+		for _, part := range strings.Split(value, ",") {
+			iface.DNS = append(iface.DNS, strings.TrimSpace(part))
+		}
+	case "mtu":
+		mtu, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid MTU: %w", err)
+		}
+		iface.MTU = uint16(mtu)
+	case "table":
+		iface.Table = value
+	case "preup":
+		iface.PreUp = value
+	case "postup":
+		iface.PostUp = value
+	case "predown":
+		iface.PreDown = value
+	case "postdown":
+		iface.PostDown = value
+	case "saveconfig":
+		save, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid SaveConfig: %w", err)
+		}
+		iface.SaveConfig = save
+	case "jc":
+		return parseUint16(value, "Jc", &iface.Obfuscation.Jc)
+	case "jmin":
+		return parseUint16(value, "Jmin", &iface.Obfuscation.Jmin)
+	case "jmax":
+		return parseUint16(value, "Jmax", &iface.Obfuscation.Jmax)
+	case "s1":
+		return parseUint16(value, "S1", &iface.Obfuscation.S1)
+	case "s2":
+		return parseUint16(value, "S2", &iface.Obfuscation.S2)
+	case "h1":
+		return parseUint32(value, "H1", &iface.Obfuscation.H1)
+	case "h2":
+		return parseUint32(value, "H2", &iface.Obfuscation.H2)
+	case "h3":
+		return parseUint32(value, "H3", &iface.Obfuscation.H3)
+	case "h4":
+		return parseUint32(value, "H4", &iface.Obfuscation.H4)
+	default:
+		return fmt.Errorf("unknown key %q in [Interface] section", key)
+	}
+	return nil
+}
+
+func parseUint16(value, name string, dst *uint16) error {
+	v, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*dst = uint16(v)
+	return nil
+}
+
+func parseUint32(value, name string, dst *uint32) error {
+	v, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*dst = uint32(v)
+	return nil
+}
+
+func parsePeerKey(peer *Peer, key, value string) error {
+	switch strings.ToLower(key) {
+	case "publickey":
+		k, err := wgtypes.ParseKey(value)
+		if err != nil {
+			return fmt.Errorf("invalid PublicKey: %w", err)
+		}
+		peer.PublicKey = k
+	case "presharedkey":
+		k, err := wgtypes.ParseKey(value)
+		if err != nil {
+			return fmt.Errorf("invalid PresharedKey: %w", err)
+		}
+		peer.PresharedKey = k
+	case "allowedips":
+		for _, part := range strings.Split(value, ",") {
+			ip, err := ParseIPCidr(part)
+			if err != nil {
+				return fmt.Errorf("invalid AllowedIPs: %w", err)
+			}
+			peer.AllowedIPs = append(peer.AllowedIPs, ip)
+		}
+	case "endpoint":
+		ep, err := ParseEndpoint(value)
+		if err != nil {
+			return fmt.Errorf("invalid Endpoint: %w", err)
+		}
+		peer.Endpoint = ep
+	case "persistentkeepalive":
+		alive, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid PersistentKeepalive: %w", err)
+		}
+		peer.PersistentKeepalive = uint16(alive)
+	default:
+		return fmt.Errorf("unknown key %q in [Peer] section", key)
+	}
+	return nil
+}
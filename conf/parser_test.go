@@ -0,0 +1,124 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func TestFromWgQuickRoundTrip(t *testing.T) {
+	priv, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := "[Interface]\n" +
+		"PrivateKey = " + priv.String() + "\n" +
+		"Address = 10.0.0.2/24, fd00::2/64\n" +
+		"ListenPort = 51820\n" +
+		"DNS = 1.1.1.1, 1.0.0.1\n" +
+		"Jc = 4\n" +
+		"Jmin = 1\n" +
+		"Jmax = 3\n" +
+		"\n" +
+		"[Peer]\n" +
+		"PublicKey = " + pub.PublicKey().String() + "\n" +
+		"AllowedIPs = 0.0.0.0/0\n" +
+		"Endpoint = example.com:51820\n" +
+		"PersistentKeepalive = 25\n"
+
+	cfg, err := FromWgQuick([]byte(data), "wg0")
+	if err != nil {
+		t.Fatalf("FromWgQuick: %v", err)
+	}
+
+	if cfg.Interface.PrivateKey != priv {
+		t.Errorf("PrivateKey = %v, want %v", cfg.Interface.PrivateKey, priv)
+	}
+	if cfg.Interface.ListenPort != 51820 {
+		t.Errorf("ListenPort = %d, want 51820", cfg.Interface.ListenPort)
+	}
+	if len(cfg.Interface.Addresses) != 2 {
+		t.Fatalf("Addresses = %v, want 2 entries", cfg.Interface.Addresses)
+	}
+	if got := cfg.Interface.Obfuscation; got.Jc != 4 || got.Jmin != 1 || got.Jmax != 3 {
+		t.Errorf("Obfuscation = %+v, want Jc=4 Jmin=1 Jmax=3", got)
+	}
+	if len(cfg.Peers) != 1 {
+		t.Fatalf("Peers = %v, want 1 entry", cfg.Peers)
+	}
+	if cfg.Peers[0].Endpoint.Host != "example.com" || cfg.Peers[0].Endpoint.Port != 51820 {
+		t.Errorf("Endpoint = %+v, want example.com:51820", cfg.Peers[0].Endpoint)
+	}
+
+	again, err := FromWgQuick([]byte(cfg.ToWgQuick()), "wg0")
+	if err != nil {
+		t.Fatalf("FromWgQuick(ToWgQuick()): %v", err)
+	}
+	if got, want := again.ToWgQuick(), cfg.ToWgQuick(); got != want {
+		t.Errorf("ToWgQuick after a round trip = %q, want %q", got, want)
+	}
+}
+
+func TestFromWgQuickErrorLineAndColumn(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:       "malformed line at start of file",
+			data:       "[Interface]\ngarbage\n",
+			wantLine:   2,
+			wantColumn: 1,
+		},
+		{
+			name:       "malformed line with leading whitespace",
+			data:       "[Interface]\n    garbage\n",
+			wantLine:   2,
+			wantColumn: 5,
+		},
+		{
+			name:       "bad key=value with leading whitespace",
+			data:       "[Interface]\n  ListenPort = notanumber\n",
+			wantLine:   2,
+			wantColumn: 15,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromWgQuick([]byte(tt.data), "wg0")
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+
+			perr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("error is %T, want *ParseError", err)
+			}
+			if perr.Line != tt.wantLine || perr.Column != tt.wantColumn {
+				t.Errorf("got line %d column %d, want line %d column %d", perr.Line, perr.Column, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestFromWgQuickMissingInterface(t *testing.T) {
+	pub, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := "[Peer]\nPublicKey = " + pub.PublicKey().String() + "\n"
+	_, err = FromWgQuick([]byte(data), "wg0")
+	if err == nil || !strings.Contains(err.Error(), "missing [Interface]") {
+		t.Fatalf("got %v, want a missing [Interface] error", err)
+	}
+}
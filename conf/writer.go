@@ -0,0 +1,93 @@
+package conf
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// ToWgQuick renders the config back into wg-quick's [Interface]/[Peer] INI
+// format, writing only the fields that are non-zero. The obfuscation keys
+// are written as a group: once any of them is non-zero, all nine are
+// written, zeros included.
+func (c *Config) ToWgQuick() string {
+	var buf strings.Builder
+	var zeroKey wgtypes.Key
+
+	buf.WriteString("[Interface]\n")
+	if c.Interface.PrivateKey != zeroKey {
+		buf.WriteString("PrivateKey = " + c.Interface.PrivateKey.String() + "\n")
+	}
+	if c.Interface.ListenPort != 0 {
+		buf.WriteString("ListenPort = " + strconv.Itoa(int(c.Interface.ListenPort)) + "\n")
+	}
+	if len(c.Interface.Addresses) > 0 {
+		buf.WriteString("Address = " + joinIPCidrs(c.Interface.Addresses) + "\n")
+	}
+	if len(c.Interface.DNS) > 0 {
+		buf.WriteString("DNS = " + strings.Join(c.Interface.DNS, ", ") + "\n")
+	}
+	if c.Interface.MTU != 0 {
+		buf.WriteString("MTU = " + strconv.Itoa(int(c.Interface.MTU)) + "\n")
+	}
+	if c.Interface.Table != "" {
+		buf.WriteString("Table = " + c.Interface.Table + "\n")
+	}
+	if c.Interface.PreUp != "" {
+		buf.WriteString("PreUp = " + c.Interface.PreUp + "\n")
+	}
+	if c.Interface.PostUp != "" {
+		buf.WriteString("PostUp = " + c.Interface.PostUp + "\n")
+	}
+	if c.Interface.PreDown != "" {
+		buf.WriteString("PreDown = " + c.Interface.PreDown + "\n")
+	}
+	if c.Interface.PostDown != "" {
+		buf.WriteString("PostDown = " + c.Interface.PostDown + "\n")
+	}
+	if c.Interface.SaveConfig {
+		buf.WriteString("SaveConfig = true\n")
+	}
+	if !c.Interface.Obfuscation.IsZero() {
+		o := c.Interface.Obfuscation
+		buf.WriteString("Jc = " + strconv.Itoa(int(o.Jc)) + "\n")
+		buf.WriteString("Jmin = " + strconv.Itoa(int(o.Jmin)) + "\n")
+		buf.WriteString("Jmax = " + strconv.Itoa(int(o.Jmax)) + "\n")
+		buf.WriteString("S1 = " + strconv.Itoa(int(o.S1)) + "\n")
+		buf.WriteString("S2 = " + strconv.Itoa(int(o.S2)) + "\n")
+		buf.WriteString("H1 = " + strconv.FormatUint(uint64(o.H1), 10) + "\n")
+		buf.WriteString("H2 = " + strconv.FormatUint(uint64(o.H2), 10) + "\n")
+		buf.WriteString("H3 = " + strconv.FormatUint(uint64(o.H3), 10) + "\n")
+		buf.WriteString("H4 = " + strconv.FormatUint(uint64(o.H4), 10) + "\n")
+	}
+
+	for _, peer := range c.Peers {
+		buf.WriteString("\n[Peer]\n")
+		if peer.PublicKey != zeroKey {
+			buf.WriteString("PublicKey = " + peer.PublicKey.String() + "\n")
+		}
+		if peer.PresharedKey != zeroKey {
+			buf.WriteString("PresharedKey = " + peer.PresharedKey.String() + "\n")
+		}
+		if len(peer.AllowedIPs) > 0 {
+			buf.WriteString("AllowedIPs = " + joinIPCidrs(peer.AllowedIPs) + "\n")
+		}
+		if !peer.Endpoint.IsEmpty() {
+			buf.WriteString("Endpoint = " + peer.Endpoint.String() + "\n")
+		}
+		if peer.PersistentKeepalive != 0 {
+			buf.WriteString("PersistentKeepalive = " + strconv.Itoa(int(peer.PersistentKeepalive)) + "\n")
+		}
+	}
+
+	return buf.String()
+}
+
+func joinIPCidrs(ips []IPCidr) string {
+	parts := make([]string, len(ips))
+	for i, ip := range ips {
+		parts[i] = ip.String()
+	}
+	return strings.Join(parts, ", ")
+}
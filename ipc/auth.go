@@ -0,0 +1,107 @@
+package ipc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"golang.org/x/sys/unix"
+)
+
+// polkitAction must match the action id declared in
+// policy/org.freedesktop.wirewizard.policy, installed by the DEB builder.
+const polkitAction = "org.freedesktop.wirewizard.manage-tunnel"
+
+// peerCredentials reads the uid/gid/pid of the process on the other end of
+// conn via SO_PEERCRED.
+func peerCredentials(conn *net.UnixConn) (*unix.Ucred, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var cred *unix.Ucred
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, sockErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return nil, err
+	}
+
+	return cred, sockErr
+}
+
+// authorize asks polkit whether the process behind cred may perform
+// polkitAction, via org.freedesktop.PolicyKit1.
+func authorize(cred *unix.Ucred) (bool, error) {
+	if cred.Uid == 0 {
+		return true, nil
+	}
+
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return false, fmt.Errorf("ipc: connecting to system bus: %w", err)
+	}
+	defer conn.Close()
+
+	startTime, err := processStartTime(cred.Pid)
+	if err != nil {
+		return false, fmt.Errorf("ipc: reading process start time: %w", err)
+	}
+
+	subject := struct {
+		Kind    string
+		Details map[string]dbus.Variant
+	}{
+		Kind: "unix-process",
+		Details: map[string]dbus.Variant{
+			"pid":        dbus.MakeVariant(uint32(cred.Pid)),
+			"start-time": dbus.MakeVariant(startTime),
+		},
+	}
+
+	authority := conn.Object("org.freedesktop.PolicyKit1", dbus.ObjectPath("/org/freedesktop/PolicyKit1/Authority"))
+
+	var isAuthorized, isChallenge bool
+	var details map[string]string
+
+	err = authority.Call(
+		"org.freedesktop.PolicyKit1.Authority.CheckAuthorization", 0,
+		subject, polkitAction, map[string]string{}, uint32(1), "",
+	).Store(&isAuthorized, &isChallenge, &details)
+	if err != nil {
+		return false, fmt.Errorf("ipc: checking polkit authorization: %w", err)
+	}
+
+	return isAuthorized, nil
+}
+
+// processStartTime reads field 22 (starttime, in clock ticks since boot)
+// of /proc/<pid>/stat. PolicyKit keys its "unix-process" subject on the
+// (pid, start-time) pair specifically so that a PID that's been reused by
+// a different process since the check was requested doesn't inherit a
+// stale authorization; a hardcoded start-time of 0 defeats that.
+func processStartTime(pid int32) (uint64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// comm (the 2nd field) is parenthesized and may itself contain spaces
+	// or ')', so find the last ')' before splitting the rest on whitespace.
+	end := strings.LastIndexByte(string(data), ')')
+	if end < 0 {
+		return 0, fmt.Errorf("ipc: malformed /proc/%d/stat", pid)
+	}
+
+	fields := strings.Fields(string(data)[end+1:])
+	const startTimeField = 19 // starttime is field 22 overall, field 20 after comm, 0-indexed here
+	if len(fields) <= startTimeField {
+		return 0, fmt.Errorf("ipc: /proc/%d/stat has too few fields", pid)
+	}
+
+	return strconv.ParseUint(fields[startTimeField], 10, 64)
+}
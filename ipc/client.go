@@ -0,0 +1,219 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"wirewizard/conf"
+)
+
+// Client is a thin client over the daemon's control socket. The GUI process
+// uses it instead of touching store/tunnel directly, so it never needs
+// CAP_NET_ADMIN itself.
+type Client struct {
+	socketPath string
+}
+
+// NewClient returns a Client that dials path for every call.
+func NewClient(path string) *Client {
+	return &Client{socketPath: path}
+}
+
+// DefaultClient dials the daemon's well-known socket.
+func DefaultClient() *Client {
+	return NewClient(SocketPath)
+}
+
+func (c *Client) roundTrip(req message) (message, error) {
+	conn, err := net.Dial("unix", c.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("ipc: connecting to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	if err := writeMessage(conn, req); err != nil {
+		return nil, err
+	}
+
+	resp, err := readMessage(bufio.NewReader(conn))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp["errno"] != "0" {
+		if errMsg := resp["error"]; errMsg != "" {
+			return nil, errors.New(errMsg)
+		}
+		return nil, fmt.Errorf("ipc: request failed (errno=%s)", resp["errno"])
+	}
+
+	return resp, nil
+}
+
+// ListTunnels returns the names of every tunnel known to the daemon.
+func (c *Client) ListTunnels() ([]string, error) {
+	resp, err := c.roundTrip(message{"list_tunnels": "1"})
+	if err != nil {
+		return nil, err
+	}
+
+	tunnels := resp["tunnels"]
+	if tunnels == "" {
+		return nil, nil
+	}
+	return strings.Split(tunnels, ","), nil
+}
+
+// LoadTunnelInfo fetches the named tunnel's config merged with whatever a
+// live device can tell us (actual negotiated endpoints, actual keys, etc.).
+func (c *Client) LoadTunnelInfo(name string) (*TunnelInfo, error) {
+	resp, err := c.roundTrip(message{"load_tunnel": name})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := base64.StdEncoding.DecodeString(resp["info"])
+	if err != nil {
+		return nil, err
+	}
+
+	var info TunnelInfo
+	if err := json.Unmarshal(encoded, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// Stats is a single peer's raw, unlocalized traffic counters.
+type Stats struct {
+	LastHandshakeUnix int64
+	ReceiveBytes      int64
+	TransmitBytes     int64
+}
+
+// GetStats fetches the first peer's live counters for the named tunnel.
+func (c *Client) GetStats(name string) (*Stats, error) {
+	resp, err := c.roundTrip(message{"get_stats": name})
+	if err != nil {
+		return nil, err
+	}
+
+	handshake, err := strconv.ParseInt(resp["last_handshake_unix"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	rx, err := strconv.ParseInt(resp["rx_bytes"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := strconv.ParseInt(resp["tx_bytes"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stats{LastHandshakeUnix: handshake, ReceiveBytes: rx, TransmitBytes: tx}, nil
+}
+
+// GetDeviceStats fetches every peer's live counters for the named tunnel,
+// for callers that poll continuously (e.g. a GUI's live stats view) and
+// so must never need CAP_NET_ADMIN to do it themselves.
+func (c *Client) GetDeviceStats(name string) (*DeviceStats, error) {
+	resp, err := c.roundTrip(message{"device_stats": name})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := base64.StdEncoding.DecodeString(resp["stats"])
+	if err != nil {
+		return nil, err
+	}
+
+	var stats DeviceStats
+	if err := json.Unmarshal(encoded, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// SaveTunnel seals and writes cfg under name.
+func (c *Client) SaveTunnel(name string, cfg *conf.Config) error {
+	encoded := base64.StdEncoding.EncodeToString([]byte(cfg.ToWgQuick()))
+	_, err := c.roundTrip(message{"save_tunnel": name, "config": encoded})
+	return err
+}
+
+// DeleteTunnel removes the named tunnel's config.
+func (c *Client) DeleteTunnel(name string) error {
+	_, err := c.roundTrip(message{"delete_tunnel": name})
+	return err
+}
+
+// StartTunnel brings the named tunnel up.
+func (c *Client) StartTunnel(name string) error {
+	_, err := c.roundTrip(message{"start_tunnel": name})
+	return err
+}
+
+// StopTunnel brings the named tunnel down.
+func (c *Client) StopTunnel(name string) error {
+	_, err := c.roundTrip(message{"stop_tunnel": name})
+	return err
+}
+
+// ReloadTunnel pushes the named tunnel's on-disk config into its running
+// device.
+func (c *Client) ReloadTunnel(name string) error {
+	_, err := c.roundTrip(message{"reload_tunnel": name})
+	return err
+}
+
+// GenerateKeys asks the daemon to generate a new WireGuard keypair.
+func (c *Client) GenerateKeys() (privateKey, publicKey string, err error) {
+	resp, err := c.roundTrip(message{"generate_keys": "1"})
+	if err != nil {
+		return "", "", err
+	}
+	return resp["private_key"], resp["public_key"], nil
+}
+
+// Event is a tunnel state change streamed by SubscribeEvents.
+type Event struct {
+	Interface string
+	Op        string
+}
+
+// SubscribeEvents opens a long-lived connection to the daemon and sends an
+// Event on events for every tunnel state change, until stop is closed.
+func (c *Client) SubscribeEvents(events chan<- Event, stop <-chan struct{}) error {
+	conn, err := net.Dial("unix", c.socketPath)
+	if err != nil {
+		return fmt.Errorf("ipc: connecting to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	if err := writeMessage(conn, message{"subscribe_events": "1"}); err != nil {
+		return err
+	}
+
+	go func() {
+		<-stop
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := readMessage(reader)
+		if err != nil {
+			return err
+		}
+		events <- Event{Interface: msg["interface"], Op: msg["event"]}
+	}
+}
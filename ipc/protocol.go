@@ -0,0 +1,64 @@
+// Package ipc implements the wirewizard daemon's control-socket protocol: a
+// small, UAPI-flavored key=value request/response framing used for tunnel
+// queries and tunnel-lifecycle commands (list_tunnels=1, start_tunnel=,
+// stop_tunnel=, subscribe_events=1, and so on). It borrows the framing of
+// wireguard-go's UAPI, but the standard UAPI get=1/set=1 operations are not
+// handled: the server answers them as unrecognized requests. It's modeled on
+// wireguard-windows' manager/ipc_server.go, and multiplexes every tunnel over
+// one socket instead of binding one UAPI socket per interface.
+package ipc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SocketPath is where the daemon listens and where clients dial.
+const SocketPath = "/run/wirewizard/wirewizard.sock"
+
+// message is one request or response: a flat set of key=value lines
+// terminated by a blank line, the same framing the UAPI protocol uses.
+type message map[string]string
+
+func readMessage(r *bufio.Reader) (message, error) {
+	msg := make(message)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && len(msg) == 0 {
+				return nil, io.EOF
+			}
+			if err != io.EOF {
+				return nil, err
+			}
+		}
+
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			return msg, nil
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("ipc: malformed line %q", line)
+		}
+		msg[key] = value
+	}
+}
+
+func writeMessage(w io.Writer, msg message) error {
+	var buf strings.Builder
+	for key, value := range msg {
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
@@ -0,0 +1,448 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"wirewizard/conf"
+	"wirewizard/store"
+	"wirewizard/tunnel"
+)
+
+// Server is the privileged daemon that owns CAP_NET_ADMIN and brokers every
+// tunnel operation for unprivileged frontends.
+type Server struct {
+	store *store.Store
+
+	wgOnce   sync.Once
+	wgClient *wgctrl.Client
+	wgErr    error
+
+	mu          sync.Mutex
+	subscribers map[chan message]struct{}
+}
+
+// NewServer returns a Server backed by s.
+func NewServer(s *store.Store) *Server {
+	return &Server{store: s, subscribers: make(map[chan message]struct{})}
+}
+
+// wgctrlClient returns the Server's long-lived wgctrl.Client, opening it on
+// the first call instead of once per request: wgctrl.New() opens a generic
+// netlink socket, and doing that on every stats poll was wasteful enough
+// to show up as its own complaint.
+func (s *Server) wgctrlClient() (*wgctrl.Client, error) {
+	s.wgOnce.Do(func() {
+		s.wgClient, s.wgErr = wgctrl.New()
+	})
+	return s.wgClient, s.wgErr
+}
+
+// ListenAndServe creates SocketPath, removing any stale socket left behind
+// by a previous run, and serves connections until an unrecoverable accept
+// error occurs.
+func (s *Server) ListenAndServe() error {
+	if err := os.MkdirAll(filepath.Dir(SocketPath), 0755); err != nil {
+		return err
+	}
+	os.Remove(SocketPath)
+
+	listener, err := net.Listen("unix", SocketPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(SocketPath, 0666); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go s.watchStore()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(conn.(*net.UnixConn))
+	}
+}
+
+func (s *Server) handle(conn *net.UnixConn) {
+	defer conn.Close()
+
+	cred, err := peerCredentials(conn)
+	if err != nil {
+		return
+	}
+
+	ok, err := authorize(cred)
+	if err != nil || !ok {
+		writeMessage(conn, message{"errno": "1", "error": "not authorized"})
+		return
+	}
+
+	reader := bufio.NewReader(conn)
+	for {
+		req, err := readMessage(reader)
+		if err != nil {
+			return
+		}
+
+		if req["subscribe_events"] == "1" {
+			s.streamEvents(conn)
+			return
+		}
+
+		writeMessage(conn, s.dispatch(req))
+	}
+}
+
+func (s *Server) dispatch(req message) message {
+	switch {
+	case req["list_tunnels"] == "1":
+		return s.handleList()
+	case req["load_tunnel"] != "":
+		return withValidName(req["load_tunnel"], s.handleLoad)
+	case req["get_stats"] != "":
+		return withValidName(req["get_stats"], s.handleStats)
+	case req["device_stats"] != "":
+		return withValidName(req["device_stats"], s.handleDeviceStats)
+	case req["save_tunnel"] != "":
+		return withValidName(req["save_tunnel"], func(name string) message {
+			return s.handleSave(name, req["config"])
+		})
+	case req["delete_tunnel"] != "":
+		return withValidName(req["delete_tunnel"], s.handleDelete)
+	case req["start_tunnel"] != "":
+		return withValidName(req["start_tunnel"], func(name string) message {
+			return s.handleStateChange(name, "started", func(cfg *conf.Config) error {
+				_, err := tunnel.Up(cfg)
+				return err
+			})
+		})
+	case req["stop_tunnel"] != "":
+		return withValidName(req["stop_tunnel"], func(name string) message {
+			return s.handleStateChange(name, "stopped", tunnel.Down)
+		})
+	case req["reload_tunnel"] != "":
+		return withValidName(req["reload_tunnel"], func(name string) message {
+			return s.handleStateChange(name, "reloaded", tunnel.Reload)
+		})
+	case req["generate_keys"] == "1":
+		return s.handleGenerateKeys()
+	default:
+		return message{"errno": "22", "error": "unrecognized request"}
+	}
+}
+
+// withValidName rejects tunnel names that aren't safe interface names
+// before they ever reach the store or wgctrl, since both the filesystem
+// path and the netlink device lookup are keyed directly on name and the
+// daemon runs privileged.
+func withValidName(name string, handle func(string) message) message {
+	if err := store.ValidateName(name); err != nil {
+		return message{"errno": "22", "error": err.Error()}
+	}
+	return handle(name)
+}
+
+func (s *Server) handleList() message {
+	names, err := s.store.List()
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+	return message{"errno": "0", "tunnels": strings.Join(names, ",")}
+}
+
+func (s *Server) handleLoad(name string) message {
+	cfg, err := s.store.Load(name)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	info := TunnelInfo{
+		PrivateKey:  cfg.Interface.PrivateKey.String(),
+		PublicKey:   cfg.Interface.PrivateKey.PublicKey().String(),
+		ListenPort:  int(cfg.Interface.ListenPort),
+		Address:     joinIPCidrs(cfg.Interface.Addresses),
+		DNS:         strings.Join(cfg.Interface.DNS, ","),
+		Obfuscation: cfg.Interface.Obfuscation,
+	}
+
+	info.ObfuscationSupported = tunnel.SupportsObfuscation
+
+	var device *wgtypes.Device
+	if client, err := s.wgctrlClient(); err == nil {
+		device, _ = client.Device(name)
+	}
+
+	if device != nil {
+		info.PrivateKey = device.PrivateKey.String()
+		info.PublicKey = device.PublicKey.String()
+		info.ListenPort = device.ListenPort
+	}
+
+	info.Peers = make([]PeerInfo, len(cfg.Peers))
+	for i, peer := range cfg.Peers {
+		peerInfo := PeerInfo{
+			PublicKey:           peer.PublicKey.String(),
+			PresharedKey:        peer.PresharedKey.String(),
+			Endpoint:            peer.Endpoint.String(),
+			AllowedIPs:          joinIPCidrs(peer.AllowedIPs),
+			PersistentKeepalive: int(peer.PersistentKeepalive),
+		}
+
+		if device != nil {
+			if live := findDevicePeer(device, peer.PublicKey); live != nil {
+				peerInfo.Endpoint = live.Endpoint.String()
+
+				ips := make([]string, 0, len(live.AllowedIPs))
+				for _, ipNet := range live.AllowedIPs {
+					ips = append(ips, ipNet.String())
+				}
+				peerInfo.AllowedIPs = strings.Join(ips, ",")
+			}
+		}
+
+		info.Peers[i] = peerInfo
+	}
+
+	encoded, err := json.Marshal(info)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	return message{"errno": "0", "info": base64.StdEncoding.EncodeToString(encoded)}
+}
+
+func (s *Server) handleStats(name string) message {
+	client, err := s.wgctrlClient()
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	device, err := client.Device(name)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+	if len(device.Peers) == 0 {
+		return message{"errno": "1", "error": "interface has no peers"}
+	}
+
+	peer := device.Peers[0]
+
+	var handshakeUnix int64
+	if !peer.LastHandshakeTime.IsZero() {
+		handshakeUnix = peer.LastHandshakeTime.Unix()
+	}
+
+	return message{
+		"errno":               "0",
+		"last_handshake_unix": strconv.FormatInt(handshakeUnix, 10),
+		"rx_bytes":            strconv.FormatInt(peer.ReceiveBytes, 10),
+		"tx_bytes":            strconv.FormatInt(peer.TransmitBytes, 10),
+	}
+}
+
+// handleDeviceStats returns every peer's live counters for name, for
+// frontends that poll for stats continuously (e.g. a GUI's live stats
+// view) so that polling never needs CAP_NET_ADMIN in the caller itself.
+func (s *Server) handleDeviceStats(name string) message {
+	client, err := s.wgctrlClient()
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	device, err := client.Device(name)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	stats := DeviceStats{
+		PrivateKey: device.PrivateKey.String(),
+		PublicKey:  device.PublicKey.String(),
+		ListenPort: device.ListenPort,
+		Peers:      make([]PeerStats, len(device.Peers)),
+	}
+
+	var zeroKey wgtypes.Key
+	for i, peer := range device.Peers {
+		var handshake int64
+		if !peer.LastHandshakeTime.IsZero() {
+			handshake = peer.LastHandshakeTime.Unix()
+		}
+
+		var psk string
+		if peer.PresharedKey != zeroKey {
+			psk = peer.PresharedKey.String()
+		}
+
+		var endpoint string
+		if peer.Endpoint != nil {
+			endpoint = peer.Endpoint.String()
+		}
+
+		ips := make([]string, 0, len(peer.AllowedIPs))
+		for _, ipNet := range peer.AllowedIPs {
+			ips = append(ips, ipNet.String())
+		}
+
+		stats.Peers[i] = PeerStats{
+			PublicKey:                   peer.PublicKey.String(),
+			PresharedKey:                psk,
+			Endpoint:                    endpoint,
+			AllowedIPs:                  strings.Join(ips, ","),
+			LastHandshakeUnix:           handshake,
+			ReceiveBytes:                peer.ReceiveBytes,
+			TransmitBytes:               peer.TransmitBytes,
+			PersistentKeepaliveInterval: int(peer.PersistentKeepaliveInterval.Seconds()),
+		}
+	}
+
+	encoded, err := json.Marshal(stats)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	return message{"errno": "0", "stats": base64.StdEncoding.EncodeToString(encoded)}
+}
+
+func findDevicePeer(device *wgtypes.Device, pubKey wgtypes.Key) *wgtypes.Peer {
+	for i := range device.Peers {
+		if device.Peers[i].PublicKey == pubKey {
+			return &device.Peers[i]
+		}
+	}
+	return nil
+}
+
+func joinIPCidrs(ips []conf.IPCidr) string {
+	parts := make([]string, len(ips))
+	for i, ip := range ips {
+		parts[i] = ip.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func (s *Server) handleSave(name, encodedConfig string) message {
+	data, err := base64.StdEncoding.DecodeString(encodedConfig)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	cfg, err := conf.FromWgQuick(data, name)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	if err := s.store.Save(name, cfg); err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	return message{"errno": "0"}
+}
+
+func (s *Server) handleDelete(name string) message {
+	if err := s.store.Delete(name); err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+	return message{"errno": "0"}
+}
+
+func (s *Server) handleStateChange(name, eventOp string, apply func(*conf.Config) error) message {
+	cfg, err := s.store.Load(name)
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	if err := apply(cfg); err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+
+	s.broadcast(message{"event": eventOp, "interface": name})
+	return message{"errno": "0"}
+}
+
+func (s *Server) handleGenerateKeys() message {
+	key, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return message{"errno": "1", "error": err.Error()}
+	}
+	return message{"errno": "0", "private_key": key.String(), "public_key": key.PublicKey().String()}
+}
+
+// streamEvents blocks, forwarding every broadcast message to conn until it
+// disconnects or fails a write.
+func (s *Server) streamEvents(conn net.Conn) {
+	events := make(chan message, 16)
+
+	s.mu.Lock()
+	s.subscribers[events] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, events)
+		s.mu.Unlock()
+	}()
+
+	for event := range events {
+		if err := writeMessage(conn, event); err != nil {
+			return
+		}
+	}
+}
+
+// watchStore forwards filesystem changes to a tunnel's config file (e.g. a
+// hand edit, or wg-quick writing SaveConfig's state back out) to every
+// subscriber, the same as a start/stop/reload triggered over IPC. It never
+// returns; a failure to even start watching is logged and left at that,
+// since IPC-driven tunnel management keeps working without it.
+func (s *Server) watchStore() {
+	events := make(chan store.Event, 16)
+	go func() {
+		if err := s.store.Watch(events, nil); err != nil {
+			log.Printf("wirewizard-daemon: watching tunnel configs: %v", err)
+		}
+	}()
+
+	for event := range events {
+		s.broadcast(message{"event": watchEventName(event.Op), "interface": event.Name})
+	}
+}
+
+func watchEventName(op store.EventOp) string {
+	switch op {
+	case store.EventCreated:
+		return "config_created"
+	case store.EventModified:
+		return "config_modified"
+	case store.EventRemoved:
+		return "config_removed"
+	default:
+		return "config_changed"
+	}
+}
+
+func (s *Server) broadcast(event message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package ipc
+
+import "wirewizard/conf"
+
+// PeerInfo is one peer in a TunnelInfo, with live wgctrl state (endpoint,
+// allowed IPs) substituted in wherever the tunnel is actually running.
+type PeerInfo struct {
+	PublicKey           string
+	PresharedKey        string
+	Endpoint            string
+	AllowedIPs          string
+	PersistentKeepalive int
+}
+
+// TunnelInfo is everything the GUI needs to render a tunnel: the parsed
+// on-disk config, merged with whatever a live device can tell us.
+type TunnelInfo struct {
+	PrivateKey           string
+	PublicKey            string
+	ListenPort           int
+	Address              string
+	DNS                  string
+	Obfuscation          conf.Obfuscation
+	ObfuscationSupported bool
+	Peers                []PeerInfo
+}
+
+// PeerStats is one peer's live counters, as returned by GetDeviceStats.
+type PeerStats struct {
+	PublicKey                   string
+	PresharedKey                string
+	Endpoint                    string
+	AllowedIPs                  string
+	LastHandshakeUnix           int64
+	ReceiveBytes                int64
+	TransmitBytes               int64
+	PersistentKeepaliveInterval int
+}
+
+// DeviceStats is a tunnel's full live state, as queried from the kernel
+// device by GetDeviceStats: everything live-stats callers need to show
+// per-peer counters, without ever needing CAP_NET_ADMIN themselves.
+type DeviceStats struct {
+	PrivateKey string
+	PublicKey  string
+	ListenPort int
+	Peers      []PeerStats
+}
@@ -0,0 +1,15 @@
+package store
+
+// Sealer seals and unseals tunnel config bytes for at-rest storage.
+type Sealer interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Unseal(sealed []byte) ([]byte, error)
+}
+
+// Plaintext is a passthrough Sealer kept for backwards compatibility with
+// existing unencrypted /etc/wireguard/*.conf files.
+type Plaintext struct{}
+
+func (Plaintext) Seal(plaintext []byte) ([]byte, error) { return plaintext, nil }
+
+func (Plaintext) Unseal(sealed []byte) ([]byte, error) { return sealed, nil }
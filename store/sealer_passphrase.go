@@ -0,0 +1,97 @@
+package store
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	saltSize      = 16
+)
+
+// PassphraseSealer derives an XChaCha20-Poly1305 key from a passphrase with
+// Argon2id, using a fresh random salt and nonce on every Seal call.
+type PassphraseSealer struct {
+	Passphrase string
+}
+
+func (p PassphraseSealer) Seal(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := p.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(salt)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+func (p PassphraseSealer) Unseal(sealed []byte) ([]byte, error) {
+	if len(sealed) < saltSize {
+		return nil, errors.New("store: sealed tunnel is truncated")
+	}
+	salt, rest := sealed[:saltSize], sealed[saltSize:]
+
+	aead, err := p.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.New("store: sealed tunnel is truncated")
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func (p PassphraseSealer) aead(salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(p.Passphrase), salt, argon2Time, argon2Memory, argon2Threads, chacha20poly1305.KeySize)
+	return chacha20poly1305.NewX(key)
+}
+
+// LoadOrCreatePassphraseSealer reads the sealing passphrase from path,
+// generating a fresh random one and persisting it there (mode 0600) on
+// first run. This is the fallback used when no Secret Service session is
+// available (e.g. the daemon running outside any user's D-Bus session),
+// so the store is still encrypted at rest without an operator having to
+// supply a passphrase by hand.
+func LoadOrCreatePassphraseSealer(path string) (PassphraseSealer, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		return PassphraseSealer{Passphrase: string(data)}, nil
+	} else if !os.IsNotExist(err) {
+		return PassphraseSealer{}, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return PassphraseSealer{}, err
+	}
+	passphrase := hex.EncodeToString(key)
+
+	if err := os.WriteFile(path, []byte(passphrase), 0600); err != nil {
+		return PassphraseSealer{}, err
+	}
+
+	return PassphraseSealer{Passphrase: passphrase}, nil
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	secretServiceDest    = "org.freedesktop.secrets"
+	secretServicePath    = dbus.ObjectPath("/org/freedesktop/secrets")
+	defaultCollection    = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	secretAttributeKey   = "service"
+	secretAttributeValue = "wirewizard-store-key"
+)
+
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// SecretServiceSealer seals tunnels with a key held in the user's D-Bus
+// Secret Service (GNOME Keyring, KWallet via ksecretd, etc.), generating
+// and persisting that key on first use.
+type SecretServiceSealer struct {
+	inner PassphraseSealer
+}
+
+// NewSecretServiceSealer connects to the session Secret Service and fetches
+// (or creates) the store's encryption key.
+func NewSecretServiceSealer() (*SecretServiceSealer, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("store: connecting to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	key, err := fetchOrCreateKey(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretServiceSealer{inner: PassphraseSealer{Passphrase: key}}, nil
+}
+
+func (s *SecretServiceSealer) Seal(plaintext []byte) ([]byte, error) {
+	return s.inner.Seal(plaintext)
+}
+
+func (s *SecretServiceSealer) Unseal(sealed []byte) ([]byte, error) {
+	return s.inner.Unseal(sealed)
+}
+
+func fetchOrCreateKey(conn *dbus.Conn) (string, error) {
+	service := conn.Object(secretServiceDest, secretServicePath)
+	attrs := map[string]string{secretAttributeKey: secretAttributeValue}
+
+	var unlocked, locked []dbus.ObjectPath
+	if err := service.Call("org.freedesktop.Secret.Service.SearchItems", 0, attrs).Store(&unlocked, &locked); err != nil {
+		return "", fmt.Errorf("store: searching secret service: %w", err)
+	}
+
+	session, err := openPlainSession(service)
+	if err != nil {
+		return "", err
+	}
+
+	if len(unlocked) > 0 {
+		return readSecret(conn, session, unlocked[0])
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	keyHex := hex.EncodeToString(key)
+
+	if err := createSecret(conn, session, attrs, keyHex); err != nil {
+		return "", err
+	}
+
+	return keyHex, nil
+}
+
+func openPlainSession(service dbus.BusObject) (dbus.ObjectPath, error) {
+	var output dbus.Variant
+	var session dbus.ObjectPath
+
+	err := service.Call("org.freedesktop.Secret.Service.OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &session)
+	if err != nil {
+		return "", fmt.Errorf("store: opening secret service session: %w", err)
+	}
+
+	return session, nil
+}
+
+func readSecret(conn *dbus.Conn, session, item dbus.ObjectPath) (string, error) {
+	var sec secret
+
+	obj := conn.Object(secretServiceDest, item)
+	if err := obj.Call("org.freedesktop.Secret.Item.GetSecret", 0, session).Store(&sec); err != nil {
+		return "", fmt.Errorf("store: reading secret: %w", err)
+	}
+
+	return string(sec.Value), nil
+}
+
+func createSecret(conn *dbus.Conn, session dbus.ObjectPath, attrs map[string]string, value string) error {
+	sec := secret{Session: session, Value: []byte(value), ContentType: "text/plain"}
+	properties := map[string]dbus.Variant{
+		"org.freedesktop.Secret.Item.Label":      dbus.MakeVariant("wirewizard tunnel store key"),
+		"org.freedesktop.Secret.Item.Attributes": dbus.MakeVariant(attrs),
+	}
+
+	collection := conn.Object(secretServiceDest, defaultCollection)
+	var item, prompt dbus.ObjectPath
+	err := collection.Call("org.freedesktop.Secret.Collection.CreateItem", 0, properties, sec, true).Store(&item, &prompt)
+	if err != nil {
+		return fmt.Errorf("store: creating secret: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlaintextSealerIsPassthrough(t *testing.T) {
+	var s Plaintext
+	plaintext := []byte("hello")
+
+	sealed, err := s.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !bytes.Equal(sealed, plaintext) {
+		t.Errorf("Seal(%q) = %q, want unchanged", plaintext, sealed)
+	}
+
+	unsealed, err := s.Unseal(sealed)
+	if err != nil {
+		t.Fatalf("Unseal: %v", err)
+	}
+	if !bytes.Equal(unsealed, plaintext) {
+		t.Errorf("Unseal(%q) = %q, want unchanged", sealed, unsealed)
+	}
+}
+
+func TestPassphraseSealerRoundTrip(t *testing.T) {
+	s := PassphraseSealer{Passphrase: "correct horse battery staple"}
+	plaintext := []byte("[Interface]\nPrivateKey = secret\n")
+
+	sealed, err := s.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Error("sealed output contains the plaintext verbatim")
+	}
+
+	unsealed, err := s.Unseal(sealed)
+	if err != nil {
+		t.Fatalf("Unseal: %v", err)
+	}
+	if !bytes.Equal(unsealed, plaintext) {
+		t.Errorf("Unseal(Seal(%q)) = %q", plaintext, unsealed)
+	}
+}
+
+func TestPassphraseSealerWrongPassphraseFails(t *testing.T) {
+	sealed, err := (PassphraseSealer{Passphrase: "right"}).Seal([]byte("data"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	if _, err := (PassphraseSealer{Passphrase: "wrong"}).Unseal(sealed); err == nil {
+		t.Fatal("Unseal with the wrong passphrase: expected an error, got nil")
+	}
+}
+
+func TestPassphraseSealerRejectsTruncatedInput(t *testing.T) {
+	s := PassphraseSealer{Passphrase: "x"}
+
+	if _, err := s.Unseal(nil); err == nil {
+		t.Fatal("Unseal(nil): expected an error, got nil")
+	}
+	if _, err := s.Unseal([]byte("short")); err == nil {
+		t.Fatal("Unseal(short): expected an error, got nil")
+	}
+}
+
+func TestLoadOrCreatePassphraseSealerPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sealkey")
+
+	first, err := LoadOrCreatePassphraseSealer(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreatePassphraseSealer: %v", err)
+	}
+
+	if info, err := os.Stat(path); err != nil {
+		t.Fatalf("stat key file: %v", err)
+	} else if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("key file mode = %o, want 0600", perm)
+	}
+
+	second, err := LoadOrCreatePassphraseSealer(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreatePassphraseSealer (second call): %v", err)
+	}
+
+	if first.Passphrase != second.Passphrase {
+		t.Error("a second call generated a different passphrase instead of reusing the persisted one")
+	}
+}
@@ -0,0 +1,155 @@
+// Package store manages tunnel configuration files under /etc/wireguard,
+// sealing their contents at rest with a pluggable Sealer so that private
+// keys and preshared keys don't have to sit on disk in plaintext. This
+// mirrors the role of wireguard-windows' DPAPI-backed conf/store.go, but
+// Linux has no single platform-native secret API, hence the Sealer
+// indirection.
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"wirewizard/conf"
+)
+
+const defaultDir = "/etc/wireguard"
+
+// defaultKeyPath is where LoadOrCreatePassphraseSealer's fallback key is
+// kept when no Secret Service session is available to the daemon.
+const defaultKeyPath = defaultDir + "/.sealkey"
+
+// DefaultSealer picks the best available at-rest Sealer for the daemon:
+// the user's D-Bus Secret Service if one is reachable, falling back to a
+// machine-generated passphrase file otherwise.
+func DefaultSealer() (Sealer, error) {
+	if sealer, err := NewSecretServiceSealer(); err == nil {
+		return sealer, nil
+	}
+
+	sealer, err := LoadOrCreatePassphraseSealer(defaultKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	return sealer, nil
+}
+
+// Store reads and writes tunnel configs from dir, sealing their contents
+// at rest with sealer.
+type Store struct {
+	dir    string
+	sealer Sealer
+}
+
+// New returns a Store rooted at the default /etc/wireguard directory.
+func New(sealer Sealer) *Store {
+	return &Store{dir: defaultDir, sealer: sealer}
+}
+
+// List returns the names (without the .conf suffix) of every tunnel in the
+// store, sorted alphabetically.
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".conf") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".conf"))
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads and unseals the named tunnel's config. If sealer can't make
+// sense of the file, it falls back to treating it as a legacy plaintext
+// wg-quick config (the format every existing /etc/wireguard/*.conf is in
+// before this daemon ever touches it, and what wg-quick or a hand edit
+// still produce) so switching to a real Sealer doesn't break tunnels that
+// already exist on disk. A legacy tunnel is re-sealed the next time it's
+// saved.
+func (s *Store) Load(name string) (*conf.Config, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.sealer.Unseal(raw)
+	if err != nil {
+		if cfg, plainErr := conf.FromWgQuick(raw, name); plainErr == nil {
+			return cfg, nil
+		}
+		return nil, err
+	}
+
+	return conf.FromWgQuick(data, name)
+}
+
+// Save seals cfg and writes it to the named tunnel's config file,
+// replacing it atomically.
+func (s *Store) Save(name string, cfg *conf.Config) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := s.sealer.Seal([]byte(cfg.ToWgQuick()))
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, sealed, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Delete removes the named tunnel's config file.
+func (s *Store) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// validNameRE matches the interface-name charset wg-quick itself accepts,
+// which rules out "/" and so anything that could escape dir when joined
+// into a path. Dots are allowed, but a name such as ".." still names a
+// plain file inside dir once ".conf" is appended.
+var validNameRE = regexp.MustCompile(`^[a-zA-Z0-9_=+.-]{1,15}$`)
+
+// ValidateName reports whether name is safe to use as a tunnel/interface
+// name: callers that take a name over IPC from an unprivileged client
+// must check this before handing it to the store or to wgctrl, since the
+// daemon runs privileged and a name like "../../etc/shadow" would
+// otherwise escape the store directory.
+func ValidateName(name string) error {
+	if !validNameRE.MatchString(name) {
+		return fmt.Errorf("store: invalid tunnel name %q", name)
+	}
+	return nil
+}
+
+func (s *Store) path(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, name+".conf"), nil
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"wirewizard/conf"
+)
+
+func newTestStore(t *testing.T, sealer Sealer) *Store {
+	return &Store{dir: t.TempDir(), sealer: sealer}
+}
+
+func TestStoreSaveLoadDelete(t *testing.T) {
+	priv, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestStore(t, Plaintext{})
+	cfg := &conf.Config{Name: "wg0", Interface: conf.Interface{PrivateKey: priv}}
+
+	if err := s.Save("wg0", cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	names, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(names) != 1 || names[0] != "wg0" {
+		t.Fatalf("List = %v, want [wg0]", names)
+	}
+
+	loaded, err := s.Load("wg0")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Interface.PrivateKey != priv {
+		t.Errorf("loaded PrivateKey = %v, want %v", loaded.Interface.PrivateKey, priv)
+	}
+
+	if err := s.Delete("wg0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Load("wg0"); err == nil {
+		t.Fatal("Load after Delete: expected an error")
+	}
+}
+
+func TestStoreLoadMigratesLegacyPlaintext(t *testing.T) {
+	priv, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestStore(t, PassphraseSealer{Passphrase: "correct horse battery staple"})
+	cfg := &conf.Config{Name: "wg0", Interface: conf.Interface{PrivateKey: priv}}
+
+	// Simulate a tunnel that predates the daemon sealing anything: its
+	// .conf file is raw wg-quick, written directly rather than through
+	// Save, so s.sealer.Unseal will fail on it.
+	path := filepath.Join(s.dir, "wg0.conf")
+	if err := os.WriteFile(path, []byte(cfg.ToWgQuick()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := s.Load("wg0")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Interface.PrivateKey != priv {
+		t.Errorf("loaded PrivateKey = %v, want %v", loaded.Interface.PrivateKey, priv)
+	}
+
+	// Saving it back should re-seal it so it no longer needs the fallback.
+	if err := s.Save("wg0", loaded); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	resealed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resealed) == cfg.ToWgQuick() {
+		t.Error("Save did not reseal the migrated config")
+	}
+}
+
+func TestStoreRejectsUnsafeNames(t *testing.T) {
+	s := newTestStore(t, Plaintext{})
+	cfg := &conf.Config{Name: "evil"}
+
+	names := []string{"../etc/shadow", "../../etc/shadow", "a/b", "", "this-name-is-way-too-long-for-an-iface"}
+	for _, name := range names {
+		if err := s.Save(name, cfg); err == nil {
+			t.Errorf("Save(%q): expected an error, got nil", name)
+		}
+		if _, err := s.Load(name); err == nil {
+			t.Errorf("Load(%q): expected an error, got nil", name)
+		}
+		if err := s.Delete(name); err == nil {
+			t.Errorf("Delete(%q): expected an error, got nil", name)
+		}
+	}
+}
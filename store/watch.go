@@ -0,0 +1,78 @@
+package store
+
+import (
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// EventOp describes what happened to a tunnel's config file.
+type EventOp int
+
+const (
+	EventCreated EventOp = iota
+	EventModified
+	EventRemoved
+)
+
+// Event is sent on the channel passed to Watch whenever a tunnel's config
+// file changes on disk, e.g. because another frontend or wg-quick itself
+// edited it directly.
+type Event struct {
+	Name string
+	Op   EventOp
+}
+
+// Watch blocks, sending an Event for every *.conf file created, modified or
+// removed under the store's directory, until stop is closed.
+func (s *Store) Watch(events chan<- Event, stop <-chan struct{}) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC)
+	if err != nil {
+		return err
+	}
+
+	if _, err := unix.InotifyAddWatch(fd, s.dir, unix.IN_CREATE|unix.IN_MODIFY|unix.IN_DELETE|unix.IN_MOVED_TO|unix.IN_MOVED_FROM); err != nil {
+		unix.Close(fd)
+		return err
+	}
+
+	go func() {
+		<-stop
+		unix.Close(fd)
+	}()
+
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+
+	for {
+		n, err := unix.Read(fd, buf)
+		if err != nil {
+			return err
+		}
+
+		for offset := 0; offset < n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			name := strings.TrimRight(string(buf[offset+unix.SizeofInotifyEvent:offset+unix.SizeofInotifyEvent+int(raw.Len)]), "\x00")
+			offset += unix.SizeofInotifyEvent + int(raw.Len)
+
+			if !strings.HasSuffix(name, ".conf") {
+				continue
+			}
+			name = strings.TrimSuffix(name, ".conf")
+
+			var op EventOp
+			switch {
+			case raw.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+				op = EventCreated
+			case raw.Mask&unix.IN_MODIFY != 0:
+				op = EventModified
+			case raw.Mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+				op = EventRemoved
+			default:
+				continue
+			}
+
+			events <- Event{Name: name, Op: op}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tunnel
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	resolvedDest = "org.freedesktop.resolve1"
+	resolvedPath = dbus.ObjectPath("/org/freedesktop/resolve1")
+)
+
+// setDNS points ifaceName's resolvers at servers, preferring
+// systemd-resolved (so other interfaces keep their own DNS) and falling
+// back to rewriting /etc/resolv.conf when resolved isn't running. It
+// returns a revert func that undoes exactly this change, for Down to call
+// so the system doesn't stay pointed at the tunnel's DNS once it's gone.
+func setDNS(ifaceName string, servers []string) (revert func() error, err error) {
+	if revert, err := setDNSViaResolved(ifaceName, servers); err == nil {
+		return revert, nil
+	}
+	return setDNSViaResolvConf(servers)
+}
+
+func setDNSViaResolved(ifaceName string, servers []string) (func() error, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	link, err := net.InterfaceByName(ifaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	type linkDNS struct {
+		Family  int32
+		Address []byte
+	}
+
+	entries := make([]linkDNS, 0, len(servers))
+	for _, server := range servers {
+		ip := net.ParseIP(strings.TrimSpace(server))
+		if ip == nil {
+			continue
+		}
+		if v4 := ip.To4(); v4 != nil {
+			entries = append(entries, linkDNS{Family: 2, Address: v4})
+		} else {
+			entries = append(entries, linkDNS{Family: 10, Address: ip.To16()})
+		}
+	}
+
+	obj := conn.Object(resolvedDest, resolvedPath)
+	if err := obj.Call("org.freedesktop.resolve1.Manager.SetLinkDNS", 0, int32(link.Index), entries).Err; err != nil {
+		return nil, err
+	}
+
+	linkIndex := int32(link.Index)
+	return func() error {
+		conn, err := dbus.ConnectSystemBus()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		return conn.Object(resolvedDest, resolvedPath).
+			Call("org.freedesktop.resolve1.Manager.RevertLink", 0, linkIndex).Err
+	}, nil
+}
+
+func setDNSViaResolvConf(servers []string) (func() error, error) {
+	previous, err := os.ReadFile("/etc/resolv.conf")
+	hadFile := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var buf strings.Builder
+	for _, server := range servers {
+		buf.WriteString("nameserver " + strings.TrimSpace(server) + "\n")
+	}
+	if err := os.WriteFile("/etc/resolv.conf", []byte(buf.String()), 0644); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		if !hadFile {
+			return os.Remove("/etc/resolv.conf")
+		}
+		return os.WriteFile("/etc/resolv.conf", previous, 0644)
+	}, nil
+}
@@ -0,0 +1,95 @@
+package tunnel
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+
+	"wirewizard/conf"
+)
+
+// configureNetwork assigns the interface's addresses and each peer's
+// AllowedIPs routes via rtnetlink, brings the link up, then points DNS at
+// the tunnel's resolvers, if any. The returned revert func undoes the DNS
+// change only (it's nil if no DNS was configured); addresses and routes
+// disappear on their own when the interface goes away in Down.
+//
+// Setting Table = off in the config opts out of this automatic routing
+// entirely, leaving it to PreUp/PostUp, same as wg-quick.
+func configureNetwork(ifaceName string, cfg *conf.Config) (revertDNS func() error, err error) {
+	link, err := netlink.LinkByName(ifaceName)
+	if err != nil {
+		return nil, fmt.Errorf("looking up link %s: %w", ifaceName, err)
+	}
+
+	for _, addr := range cfg.Interface.Addresses {
+		nlAddr := &netlink.Addr{IPNet: &net.IPNet{IP: addr.IP, Mask: cidrMask(addr)}}
+		if err := netlink.AddrAdd(link, nlAddr); err != nil {
+			return nil, fmt.Errorf("adding address %s: %w", addr, err)
+		}
+	}
+
+	if err := netlink.LinkSetUp(link); err != nil {
+		return nil, fmt.Errorf("setting link up: %w", err)
+	}
+
+	if cfg.Interface.Table != "off" {
+		for _, peer := range cfg.Peers {
+			for _, allowed := range peer.AllowedIPs {
+				for _, dst := range routeDestinations(allowed) {
+					route := &netlink.Route{
+						LinkIndex: link.Attrs().Index,
+						Dst:       dst,
+					}
+					if err := netlink.RouteAdd(route); err != nil {
+						return nil, fmt.Errorf("adding route %s: %w", dst, err)
+					}
+				}
+			}
+		}
+	}
+
+	if len(cfg.Interface.DNS) > 0 {
+		revertDNS, err = setDNS(ifaceName, cfg.Interface.DNS)
+		if err != nil {
+			return nil, fmt.Errorf("configuring DNS: %w", err)
+		}
+	}
+
+	return revertDNS, nil
+}
+
+// routeDestinations returns the *net.IPNet(s) to route for a single
+// AllowedIPs entry. A default route (0.0.0.0/0 or ::/0) is split into two
+// /1 routes, exactly as wg-quick does, so it takes priority over the
+// existing default route instead of colliding with it or requiring it to
+// be replaced outright.
+func routeDestinations(allowed conf.IPCidr) []*net.IPNet {
+	ip, bits := allowed.IP.To4(), 32
+	if ip == nil {
+		ip, bits = allowed.IP.To16(), 128
+	}
+
+	if int(allowed.Cidr) != 0 || !allowed.IP.IsUnspecified() {
+		return []*net.IPNet{{IP: allowed.IP, Mask: net.CIDRMask(int(allowed.Cidr), bits)}}
+	}
+
+	lower := make(net.IP, len(ip))
+	upper := make(net.IP, len(ip))
+	copy(upper, ip)
+	upper[0] |= 0x80
+
+	return []*net.IPNet{
+		{IP: lower, Mask: net.CIDRMask(1, bits)},
+		{IP: upper, Mask: net.CIDRMask(1, bits)},
+	}
+}
+
+func cidrMask(c conf.IPCidr) net.IPMask {
+	bits := 32
+	if c.IP.To4() == nil {
+		bits = 128
+	}
+	return net.CIDRMask(int(c.Cidr), bits)
+}
@@ -0,0 +1,151 @@
+// Package tunnel brings tunnels up and down in-process using the
+// wireguard-go userspace implementation, so starting/stopping a tunnel no
+// longer depends on shelling out to wg-quick.
+package tunnel
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+	"golang.zx2c4.com/wireguard/tun"
+
+	"wirewizard/conf"
+)
+
+const defaultMTU = 1420
+
+// SupportsObfuscation reports whether this build's in-process engine
+// actually applies AmneziaWG's junk-packet/header-obfuscation parameters
+// (Jc/Jmin/Jmax/S1/S2/H1-H4) when bringing a tunnel up. Up uses the
+// vanilla upstream wireguard-go device, whose UAPI handling (see
+// ipcConfig in uapi.go) doesn't implement those fields yet, so a config
+// that sets them is still accepted and stored but brought up without
+// obfuscation.
+const SupportsObfuscation = false
+
+// Tunnel is a running in-process WireGuard interface.
+type Tunnel struct {
+	Name      string
+	tun       tun.Device
+	device    *device.Device
+	revertDNS func() error
+}
+
+var (
+	mu      sync.Mutex
+	tunnels = make(map[string]*Tunnel)
+)
+
+// Up creates a TUN device, instantiates a wireguard-go Device from cfg,
+// pushes the config to it in UAPI text form via IpcSet (in-process; no
+// per-device UAPI socket is opened), programs addresses/routes/DNS, and
+// runs PreUp/PostUp.
+func Up(cfg *conf.Config) (*Tunnel, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := tunnels[cfg.Name]; exists {
+		return nil, fmt.Errorf("tunnel: %s is already up", cfg.Name)
+	}
+
+	if err := runHook(cfg.Name, cfg.Interface.PreUp); err != nil {
+		return nil, fmt.Errorf("tunnel: PreUp failed: %w", err)
+	}
+
+	mtu := int(cfg.Interface.MTU)
+	if mtu == 0 {
+		mtu = defaultMTU
+	}
+
+	tunDevice, err := tun.CreateTUN(cfg.Name, mtu)
+	if err != nil {
+		return nil, fmt.Errorf("tunnel: creating TUN device: %w", err)
+	}
+
+	realName, err := tunDevice.Name()
+	if err != nil {
+		realName = cfg.Name
+	}
+
+	dev := device.NewDevice(tunDevice, conn.NewStdNetBind(), device.NewLogger(device.LogLevelError, realName))
+
+	if err := dev.IpcSet(ipcConfig(cfg)); err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("tunnel: pushing config over UAPI: %w", err)
+	}
+
+	if err := dev.Up(); err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("tunnel: bringing device up: %w", err)
+	}
+
+	revertDNS, err := configureNetwork(realName, cfg)
+	if err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("tunnel: configuring network: %w", err)
+	}
+
+	t := &Tunnel{Name: cfg.Name, tun: tunDevice, device: dev, revertDNS: revertDNS}
+	tunnels[cfg.Name] = t
+
+	if err := runHook(cfg.Name, cfg.Interface.PostUp); err != nil {
+		return t, fmt.Errorf("tunnel: PostUp failed: %w", err)
+	}
+
+	return t, nil
+}
+
+// Down tears down the named tunnel, running PreDown/PostDown around it.
+func Down(cfg *conf.Config) error {
+	mu.Lock()
+	t, ok := tunnels[cfg.Name]
+	if ok {
+		delete(tunnels, cfg.Name)
+	}
+	mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("tunnel: %s is not up", cfg.Name)
+	}
+
+	if err := runHook(cfg.Name, cfg.Interface.PreDown); err != nil {
+		return fmt.Errorf("tunnel: PreDown failed: %w", err)
+	}
+
+	t.device.Close()
+
+	if t.revertDNS != nil {
+		if err := t.revertDNS(); err != nil {
+			return fmt.Errorf("tunnel: reverting DNS: %w", err)
+		}
+	}
+
+	return runHook(cfg.Name, cfg.Interface.PostDown)
+}
+
+// Reload pushes cfg's keys and peers into the already-running device
+// without tearing down the TUN interface or re-running PreUp/PostUp.
+func Reload(cfg *conf.Config) error {
+	mu.Lock()
+	t, ok := tunnels[cfg.Name]
+	mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("tunnel: %s is not up", cfg.Name)
+	}
+
+	return t.device.IpcSet(ipcConfig(cfg))
+}
+
+func runHook(ifaceName, script string) error {
+	if script == "" {
+		return nil
+	}
+
+	cmd := exec.Command("/bin/sh", "-c", script)
+	cmd.Env = append(cmd.Environ(), "WG_IFACE="+ifaceName)
+	return cmd.Run()
+}
@@ -0,0 +1,65 @@
+package tunnel
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"wirewizard/conf"
+)
+
+// ipcConfig renders cfg as the UAPI wg_set text protocol understood by
+// device.Device.IpcSet.
+func ipcConfig(cfg *conf.Config) string {
+	var buf strings.Builder
+
+	buf.WriteString("private_key=" + hex.EncodeToString(cfg.Interface.PrivateKey[:]) + "\n")
+	if cfg.Interface.ListenPort != 0 {
+		buf.WriteString("listen_port=" + strconv.Itoa(int(cfg.Interface.ListenPort)) + "\n")
+	}
+	buf.WriteString("replace_peers=true\n")
+
+	var zeroKey wgtypes.Key
+	for _, peer := range cfg.Peers {
+		buf.WriteString("public_key=" + hex.EncodeToString(peer.PublicKey[:]) + "\n")
+
+		if peer.PresharedKey != zeroKey {
+			buf.WriteString("preshared_key=" + hex.EncodeToString(peer.PresharedKey[:]) + "\n")
+		}
+
+		if !peer.Endpoint.IsEmpty() {
+			if endpoint := resolveEndpoint(peer.Endpoint); endpoint != "" {
+				buf.WriteString("endpoint=" + endpoint + "\n")
+			}
+		}
+
+		buf.WriteString("replace_allowed_ips=true\n")
+		for _, ip := range peer.AllowedIPs {
+			buf.WriteString("allowed_ip=" + ip.String() + "\n")
+		}
+
+		if peer.PersistentKeepalive != 0 {
+			buf.WriteString("persistent_keepalive_interval=" + strconv.Itoa(int(peer.PersistentKeepalive)) + "\n")
+		}
+	}
+
+	return buf.String()
+}
+
+// resolveEndpoint turns a possibly-unresolved Endpoint into the "ip:port"
+// form the UAPI protocol requires.
+func resolveEndpoint(endpoint conf.Endpoint) string {
+	if ip := net.ParseIP(endpoint.Host); ip != nil {
+		return endpoint.String()
+	}
+
+	ips, err := net.LookupHost(endpoint.Host)
+	if err != nil || len(ips) == 0 {
+		return ""
+	}
+
+	return net.JoinHostPort(ips[0], strconv.Itoa(int(endpoint.Port)))
+}
@@ -9,62 +9,117 @@ typedef struct {
 } InterfacesNameResponse;
 
 typedef struct {
-	char* InterfacePrivKey;
-	char* InterfacePubKey;
-	int   InterfaceListenPort;
-	char* InterfaceAddress;
-	char* InterfaceDNS;
-	char* PeerPubKey;
-	char* PeerEndpointAddress;
-	char* PeerAllowedIPs;
-	char* PeerPersistentKeepalive;
-	char* PeerPresharedKey;
+	char* PublicKey;
+	char* PresharedKey;
+	char* EndpointAddress;
+	char* AllowedIPs;
+	char* PersistentKeepalive;
+} PeerResponse;
+
+typedef struct {
+	char*     InterfacePrivKey;
+	char*     InterfacePubKey;
+	int       InterfaceListenPort;
+	char*     InterfaceAddress;
+	char*     InterfaceDNS;
+	int       Jc;
+	int       Jmin;
+	int       Jmax;
+	int       S1;
+	int       S2;
+	long long H1;
+	long long H2;
+	long long H3;
+	long long H4;
+	int       ObfuscationSupported;
+	PeerResponse* Peers;
+	int   PeersCount;
 } ConfigResponse;
 
+typedef struct {
+	char* PublicKey;
+	char* PresharedKey;
+	char* EndpointAddress;
+	char* AllowedIPs;
+	int   PersistentKeepalive;
+} PeerRequest;
+
+typedef struct {
+	char*     InterfacePrivKey;
+	int       InterfaceListenPort;
+	char*     InterfaceAddress;
+	char*     InterfaceDNS;
+	int       Jc;
+	int       Jmin;
+	int       Jmax;
+	int       S1;
+	int       S2;
+	long long H1;
+	long long H2;
+	long long H3;
+	long long H4;
+	PeerRequest* Peers;
+	int PeersCount;
+} ConfigRequest;
+
 typedef struct {
 	char* LastHandshakeTime;
 	char* Transfer;
 } StatsResponse;
+
+typedef struct {
+	char*     PublicKey;
+	char*     Endpoint;
+	long long LastHandshakeUnixNano;
+	long long ReceiveBytes;
+	long long TransmitBytes;
+	long long ReceiveBytesDelta;
+	long long TransmitBytesDelta;
+} PeerStat;
+
+typedef struct {
+	PeerStat* Peers;
+	int       PeersCount;
+	char*     Dump;
+} StatsSample;
+
+typedef void (*StatsCallback)(int handle, StatsSample* sample);
+
+// cgo can't call a C function pointer directly from Go, so route the call
+// through this trampoline.
+static inline void wirewizard_invoke_stats_callback(StatsCallback cb, int handle, StatsSample* sample) {
+	cb(handle, sample);
+}
 */
 import "C"
 
 import (
-	"bytes"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
-	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"wirewizard/conf"
+	"wirewizard/ipc"
 )
 
+// daemon is the client for the privileged wirewizard-daemon: every tunnel
+// operation that used to touch /etc/wireguard or wgctrl directly now goes
+// through it, so this GUI-facing library never needs CAP_NET_ADMIN itself.
+var daemon = ipc.DefaultClient()
+
 //export readInterfacesName
 func readInterfacesName() *C.InterfacesNameResponse {
-	configDirs := []string{
-		"/etc/wireguard/",
-		"/usr/local/etc/wireguard/",
-	}
-
-	devices := make([]string, 0)
-
-	for _, dir := range configDirs {
-		files, err := os.ReadDir(dir)
-		if err != nil {
-			continue
-		}
-
-		for _, file := range files {
-			if !file.IsDir() && strings.HasSuffix(file.Name(), ".conf") {
-				name := strings.TrimSuffix(file.Name(), ".conf")
-				devices = append(devices, name)
-			}
-		}
-	}
+	return listTunnels()
+}
 
-	if len(devices) == 0 {
+//export listTunnels
+func listTunnels() *C.InterfacesNameResponse {
+	devices, err := daemon.ListTunnels()
+	if err != nil || len(devices) == 0 {
 		return nil
 	}
 
@@ -81,101 +136,391 @@ func readInterfacesName() *C.InterfacesNameResponse {
 	return interfaces
 }
 
+//export deleteTunnel
+func deleteTunnel(name *C.char) *C.char {
+	if err := daemon.DeleteTunnel(C.GoString(name)); err != nil {
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
 //export readConfig
 func readConfig(name *C.char) *C.ConfigResponse {
-	client, err := wgctrl.New()
+	return loadTunnel(name)
+}
+
+//export loadTunnel
+func loadTunnel(name *C.char) *C.ConfigResponse {
+	info, err := daemon.LoadTunnelInfo(C.GoString(name))
 	if err != nil {
 		return nil
 	}
-	defer client.Close()
-
-	cfg := (*C.ConfigResponse)(C.malloc(C.size_t(unsafe.Sizeof(C.ConfigResponse{}))))
-	cfg.InterfacePrivKey = C.CString("")
-	cfg.InterfacePubKey = C.CString("")
-	cfg.InterfaceListenPort = 0
-	cfg.InterfaceAddress = C.CString("")
-	cfg.InterfaceDNS = C.CString("")
-	cfg.PeerPubKey = C.CString("")
-	cfg.PeerEndpointAddress = C.CString("")
-	cfg.PeerAllowedIPs = C.CString("")
-	cfg.PeerPersistentKeepalive = C.CString("")
-	cfg.PeerPresharedKey = C.CString("")
-
-	device, err := client.Device(C.GoString(name))
+
+	resp := (*C.ConfigResponse)(C.malloc(C.size_t(unsafe.Sizeof(C.ConfigResponse{}))))
+
+	resp.InterfacePrivKey = C.CString(info.PrivateKey)
+	resp.InterfacePubKey = C.CString(info.PublicKey)
+	resp.InterfaceListenPort = C.int(info.ListenPort)
+	resp.InterfaceAddress = C.CString(info.Address)
+	resp.InterfaceDNS = C.CString(info.DNS)
+
+	obf := info.Obfuscation
+	resp.Jc = C.int(obf.Jc)
+	resp.Jmin = C.int(obf.Jmin)
+	resp.Jmax = C.int(obf.Jmax)
+	resp.S1 = C.int(obf.S1)
+	resp.S2 = C.int(obf.S2)
+	resp.H1 = C.longlong(obf.H1)
+	resp.H2 = C.longlong(obf.H2)
+	resp.H3 = C.longlong(obf.H3)
+	resp.H4 = C.longlong(obf.H4)
+	if info.ObfuscationSupported {
+		resp.ObfuscationSupported = 1
+	}
+
+	if len(info.Peers) == 0 {
+		resp.Peers = nil
+		resp.PeersCount = 0
+		return resp
+	}
+
+	cPeers := C.malloc(C.size_t(len(info.Peers)) * C.size_t(unsafe.Sizeof(C.PeerResponse{})))
+	peerSlice := (*[1 << 30]C.PeerResponse)(cPeers)[:len(info.Peers):len(info.Peers)]
+
+	for i, peer := range info.Peers {
+		peerSlice[i].PublicKey = C.CString(peer.PublicKey)
+		peerSlice[i].PresharedKey = C.CString(peer.PresharedKey)
+		peerSlice[i].EndpointAddress = C.CString(peer.Endpoint)
+		peerSlice[i].AllowedIPs = C.CString(peer.AllowedIPs)
+		peerSlice[i].PersistentKeepalive = C.CString(strconv.Itoa(peer.PersistentKeepalive))
+	}
+
+	resp.Peers = (*C.PeerResponse)(cPeers)
+	resp.PeersCount = C.int(len(info.Peers))
+	return resp
+}
+
+//export writeConfig
+func writeConfig(name *C.char, req *C.ConfigRequest) *C.char {
+	return saveTunnel(name, req)
+}
+
+//export saveTunnel
+func saveTunnel(name *C.char, req *C.ConfigRequest) *C.char {
+	ifaceName := C.GoString(name)
+
+	cfg, err := configFromRequest(ifaceName, req)
+	if err != nil {
+		return C.CString(err.Error())
+	}
+
+	if err := daemon.SaveTunnel(ifaceName, cfg); err != nil {
+		return C.CString(err.Error())
+	}
+
+	return nil
+}
+
+//export upTunnel
+func upTunnel(name *C.char) *C.char {
+	if err := daemon.StartTunnel(C.GoString(name)); err != nil {
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
+//export downTunnel
+func downTunnel(name *C.char) *C.char {
+	if err := daemon.StopTunnel(C.GoString(name)); err != nil {
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
+//export reloadTunnel
+func reloadTunnel(name *C.char) *C.char {
+	if err := daemon.ReloadTunnel(C.GoString(name)); err != nil {
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
+func configFromRequest(ifaceName string, req *C.ConfigRequest) (*conf.Config, error) {
+	cfg := &conf.Config{Name: ifaceName}
+
+	privKey, err := wgtypes.ParseKey(C.GoString(req.InterfacePrivKey))
 	if err != nil {
-		interfacePubKey, peerPubKey := parseKeys(C.GoString(name))
-		if interfacePubKey == "" && peerPubKey == "" {
-			return nil
+		return nil, err
+	}
+	cfg.Interface.PrivateKey = privKey
+	cfg.Interface.ListenPort = uint16(req.InterfaceListenPort)
+
+	for _, part := range strings.Split(C.GoString(req.InterfaceAddress), ",") {
+		if part == "" {
+			continue
+		}
+		addr, err := conf.ParseIPCidr(part)
+		if err != nil {
+			return nil, err
 		}
+		cfg.Interface.Addresses = append(cfg.Interface.Addresses, addr)
+	}
 
-		cfg.InterfacePubKey = C.CString(interfacePubKey)
-		cfg.PeerPubKey = C.CString(peerPubKey)
-		return cfg
+	for _, dns := range strings.Split(C.GoString(req.InterfaceDNS), ",") {
+		if dns != "" {
+			cfg.Interface.DNS = append(cfg.Interface.DNS, dns)
+		}
 	}
 
-	if len(device.Peers) == 0 {
-		return nil
+	cfg.Interface.Obfuscation = conf.Obfuscation{
+		Jc:   uint16(req.Jc),
+		Jmin: uint16(req.Jmin),
+		Jmax: uint16(req.Jmax),
+		S1:   uint16(req.S1),
+		S2:   uint16(req.S2),
+		H1:   uint32(req.H1),
+		H2:   uint32(req.H2),
+		H3:   uint32(req.H3),
+		H4:   uint32(req.H4),
 	}
 
-	address, dns, alive, psk := parseConfig(C.GoString(name))
+	if req.PeersCount > 0 {
+		peerSlice := (*[1 << 30]C.PeerRequest)(unsafe.Pointer(req.Peers))[:req.PeersCount:req.PeersCount]
+
+		for _, p := range peerSlice {
+			peer := conf.Peer{PersistentKeepalive: uint16(p.PersistentKeepalive)}
+
+			peer.PublicKey, err = wgtypes.ParseKey(C.GoString(p.PublicKey))
+			if err != nil {
+				return nil, err
+			}
+
+			if psk := C.GoString(p.PresharedKey); psk != "" {
+				peer.PresharedKey, err = wgtypes.ParseKey(psk)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			for _, part := range strings.Split(C.GoString(p.AllowedIPs), ",") {
+				if part == "" {
+					continue
+				}
+				ip, err := conf.ParseIPCidr(part)
+				if err != nil {
+					return nil, err
+				}
+				peer.AllowedIPs = append(peer.AllowedIPs, ip)
+			}
 
-	peer := device.Peers[0]
+			if endpoint := C.GoString(p.EndpointAddress); endpoint != "" {
+				peer.Endpoint, err = conf.ParseEndpoint(endpoint)
+				if err != nil {
+					return nil, err
+				}
+			}
 
-	ips := make([]string, 0)
-	for _, ipNet := range peer.AllowedIPs {
-		ips = append(ips, ipNet.String())
+			cfg.Peers = append(cfg.Peers, peer)
+		}
 	}
 
-	cfg.InterfacePrivKey = C.CString(device.PrivateKey.String())
-	cfg.InterfacePubKey = C.CString(device.PublicKey.String())
-	cfg.InterfaceListenPort = C.int(device.ListenPort)
-	cfg.InterfaceAddress = C.CString(address)
-	cfg.InterfaceDNS = C.CString(dns)
-	cfg.PeerPubKey = C.CString(peer.PublicKey.String())
-	cfg.PeerEndpointAddress = C.CString(peer.Endpoint.String())
-	cfg.PeerAllowedIPs = C.CString(strings.Join(ips, ","))
-	cfg.PeerPersistentKeepalive = C.CString(alive)
-	cfg.PeerPresharedKey = C.CString(psk)
-	return cfg
+	return cfg, nil
 }
 
 //export readStats
 func readStats(name *C.char) *C.StatsResponse {
-	client, err := wgctrl.New()
+	stats, err := daemon.GetStats(C.GoString(name))
 	if err != nil {
 		return nil
 	}
-	defer client.Close()
 
 	cfg := (*C.StatsResponse)(C.malloc(C.size_t(unsafe.Sizeof(C.StatsResponse{}))))
-	cfg.LastHandshakeTime = C.CString("")
-	cfg.Transfer = C.CString("")
 
-	device, err := client.Device(C.GoString(name))
-	if err != nil {
-		return nil
+	var handshake time.Time
+	if stats.LastHandshakeUnix != 0 {
+		handshake = time.Unix(stats.LastHandshakeUnix, 0)
 	}
 
-	if len(device.Peers) == 0 {
-		return nil
+	cfg.LastHandshakeTime = C.CString(parseTime(handshake))
+	cfg.Transfer = C.CString(parseTraffic(stats.ReceiveBytes, stats.TransmitBytes))
+	return cfg
+}
+
+type trafficSample struct {
+	receive, transmit int64
+}
+
+// statsStream polls the daemon's device_stats query on an interval rather
+// than querying wgctrl directly, so the process calling startStatsStream
+// never needs CAP_NET_ADMIN itself even for continuous live stats.
+type statsStream struct {
+	handle   int
+	name     string
+	interval time.Duration
+	cb       C.StatsCallback
+	stop     chan struct{}
+}
+
+var (
+	statsStreamsMu sync.Mutex
+	statsStreams   = make(map[int]*statsStream)
+	statsHandleSeq int
+)
+
+//export startStatsStream
+func startStatsStream(name *C.char, intervalMs C.int, cb C.StatsCallback) C.int {
+	statsStreamsMu.Lock()
+	statsHandleSeq++
+	handle := statsHandleSeq
+	statsStreamsMu.Unlock()
+
+	stream := &statsStream{
+		handle:   handle,
+		name:     C.GoString(name),
+		interval: time.Duration(intervalMs) * time.Millisecond,
+		cb:       cb,
+		stop:     make(chan struct{}),
 	}
 
-	peer := device.Peers[0]
+	statsStreamsMu.Lock()
+	statsStreams[handle] = stream
+	statsStreamsMu.Unlock()
 
-	cfg.LastHandshakeTime = C.CString(parseTime(peer.LastHandshakeTime))
-	cfg.Transfer = C.CString(parseTraffic(peer.ReceiveBytes, peer.TransmitBytes))
-	return cfg
+	go stream.run()
+
+	return C.int(handle)
+}
+
+//export stopStatsStream
+func stopStatsStream(handle C.int) {
+	statsStreamsMu.Lock()
+	stream, ok := statsStreams[int(handle)]
+	delete(statsStreams, int(handle))
+	statsStreamsMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	close(stream.stop)
+}
+
+func (s *statsStream) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	previous := make(map[string]trafficSample)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			stats, err := daemon.GetDeviceStats(s.name)
+			if err != nil {
+				continue
+			}
+			s.publish(stats, previous)
+		}
+	}
+}
+
+// publish builds one StatsSample on the C heap and invokes the registered
+// callback synchronously, freeing the sample once the callback returns.
+func (s *statsStream) publish(stats *ipc.DeviceStats, previous map[string]trafficSample) {
+	cPeers := C.malloc(C.size_t(len(stats.Peers)) * C.size_t(unsafe.Sizeof(C.PeerStat{})))
+	peerSlice := (*[1 << 30]C.PeerStat)(cPeers)[:len(stats.Peers):len(stats.Peers)]
+
+	for i, peer := range stats.Peers {
+		prev := previous[peer.PublicKey]
+
+		peerSlice[i] = C.PeerStat{
+			PublicKey:             C.CString(peer.PublicKey),
+			Endpoint:              C.CString(peer.Endpoint),
+			LastHandshakeUnixNano: C.longlong(peer.LastHandshakeUnix * int64(time.Second)),
+			ReceiveBytes:          C.longlong(peer.ReceiveBytes),
+			TransmitBytes:         C.longlong(peer.TransmitBytes),
+			ReceiveBytesDelta:     C.longlong(peer.ReceiveBytes - prev.receive),
+			TransmitBytesDelta:    C.longlong(peer.TransmitBytes - prev.transmit),
+		}
+
+		previous[peer.PublicKey] = trafficSample{receive: peer.ReceiveBytes, transmit: peer.TransmitBytes}
+	}
+
+	sample := (*C.StatsSample)(C.malloc(C.size_t(unsafe.Sizeof(C.StatsSample{}))))
+	sample.Peers = (*C.PeerStat)(cPeers)
+	sample.PeersCount = C.int(len(stats.Peers))
+	sample.Dump = C.CString(dumpFormat(stats))
+
+	C.wirewizard_invoke_stats_callback(s.cb, C.int(s.handle), sample)
+
+	for i := range peerSlice {
+		C.free(unsafe.Pointer(peerSlice[i].PublicKey))
+		C.free(unsafe.Pointer(peerSlice[i].Endpoint))
+	}
+	C.free(cPeers)
+	C.free(unsafe.Pointer(sample.Dump))
+	C.free(unsafe.Pointer(sample))
+}
+
+// dumpFormat renders stats in the same tab-separated layout as
+// `wg show <iface> dump`, for compatibility with external tooling.
+func dumpFormat(stats *ipc.DeviceStats) string {
+	var buf strings.Builder
+
+	buf.WriteString(stats.PrivateKey)
+	buf.WriteByte('\t')
+	buf.WriteString(stats.PublicKey)
+	buf.WriteByte('\t')
+	buf.WriteString(strconv.Itoa(stats.ListenPort))
+	buf.WriteString("\toff\n")
+
+	for _, peer := range stats.Peers {
+		psk := peer.PresharedKey
+		if psk == "" {
+			psk = "(none)"
+		}
+
+		endpoint := peer.Endpoint
+		if endpoint == "" {
+			endpoint = "(none)"
+		}
+
+		handshake := "0"
+		if peer.LastHandshakeUnix != 0 {
+			handshake = strconv.FormatInt(peer.LastHandshakeUnix, 10)
+		}
+
+		buf.WriteString(peer.PublicKey)
+		buf.WriteByte('\t')
+		buf.WriteString(psk)
+		buf.WriteByte('\t')
+		buf.WriteString(endpoint)
+		buf.WriteByte('\t')
+		buf.WriteString(peer.AllowedIPs)
+		buf.WriteByte('\t')
+		buf.WriteString(handshake)
+		buf.WriteByte('\t')
+		buf.WriteString(strconv.FormatInt(peer.ReceiveBytes, 10))
+		buf.WriteByte('\t')
+		buf.WriteString(strconv.FormatInt(peer.TransmitBytes, 10))
+		buf.WriteByte('\t')
+		buf.WriteString(strconv.Itoa(peer.PersistentKeepaliveInterval))
+		buf.WriteByte('\n')
+	}
+
+	return buf.String()
 }
 
 //export generateKeys
 func generateKeys(privKey **C.char, pubKey **C.char) *C.char {
-	generate, err := wgtypes.GeneratePrivateKey()
+	priv, pub, err := daemon.GenerateKeys()
 	if err != nil {
 		return C.CString(err.Error())
 	}
 
-	*privKey = C.CString(generate.String())
-	*pubKey = C.CString(generate.PublicKey().String())
+	*privKey = C.CString(priv)
+	*pubKey = C.CString(pub)
 
 	return nil
 }
@@ -197,36 +542,46 @@ func freeInterfacesName(interfaces *C.InterfacesNameResponse) {
 
 //export freeConfig
 func freeConfig(cfg *C.ConfigResponse) {
-	if cfg != nil {
-		if cfg.InterfacePrivKey != nil {
-			C.free(unsafe.Pointer(cfg.InterfacePrivKey))
-		}
-		if cfg.InterfacePubKey != nil {
-			C.free(unsafe.Pointer(cfg.InterfacePubKey))
-		}
-		if cfg.InterfaceAddress != nil {
-			C.free(unsafe.Pointer(cfg.InterfaceAddress))
-		}
-		if cfg.InterfaceDNS != nil {
-			C.free(unsafe.Pointer(cfg.InterfaceDNS))
-		}
-		if cfg.PeerEndpointAddress != nil {
-			C.free(unsafe.Pointer(cfg.PeerEndpointAddress))
-		}
-		if cfg.PeerPubKey != nil {
-			C.free(unsafe.Pointer(cfg.PeerPubKey))
-		}
-		if cfg.PeerAllowedIPs != nil {
-			C.free(unsafe.Pointer(cfg.PeerAllowedIPs))
-		}
-		if cfg.PeerPersistentKeepalive != nil {
-			C.free(unsafe.Pointer(cfg.PeerPersistentKeepalive))
-		}
-		if cfg.PeerPresharedKey != nil {
-			C.free(unsafe.Pointer(cfg.PeerPresharedKey))
+	if cfg == nil {
+		return
+	}
+
+	if cfg.InterfacePrivKey != nil {
+		C.free(unsafe.Pointer(cfg.InterfacePrivKey))
+	}
+	if cfg.InterfacePubKey != nil {
+		C.free(unsafe.Pointer(cfg.InterfacePubKey))
+	}
+	if cfg.InterfaceAddress != nil {
+		C.free(unsafe.Pointer(cfg.InterfaceAddress))
+	}
+	if cfg.InterfaceDNS != nil {
+		C.free(unsafe.Pointer(cfg.InterfaceDNS))
+	}
+
+	if cfg.PeersCount > 0 {
+		peerSlice := (*[1 << 30]C.PeerResponse)(unsafe.Pointer(cfg.Peers))[:cfg.PeersCount:cfg.PeersCount]
+		for i := range peerSlice {
+			if peerSlice[i].PublicKey != nil {
+				C.free(unsafe.Pointer(peerSlice[i].PublicKey))
+			}
+			if peerSlice[i].PresharedKey != nil {
+				C.free(unsafe.Pointer(peerSlice[i].PresharedKey))
+			}
+			if peerSlice[i].EndpointAddress != nil {
+				C.free(unsafe.Pointer(peerSlice[i].EndpointAddress))
+			}
+			if peerSlice[i].AllowedIPs != nil {
+				C.free(unsafe.Pointer(peerSlice[i].AllowedIPs))
+			}
+			if peerSlice[i].PersistentKeepalive != nil {
+				C.free(unsafe.Pointer(peerSlice[i].PersistentKeepalive))
+			}
 		}
-		C.free(unsafe.Pointer(cfg))
+		C.free(unsafe.Pointer(cfg.Peers))
 	}
+
+	C.free(unsafe.Pointer(cfg))
 }
 
 //export freeStats
@@ -249,75 +604,6 @@ func freeString(str *C.char) {
 	}
 }
 
-func parseConfig(interfaceName string) (string, string, string, string) {
-	var address, dns, alive, psk string
-
-	paths := []string{
-		filepath.Join("/etc/wireguard/" + interfaceName + ".conf"),
-		filepath.Join("/usr/local/etc/wireguard/" + interfaceName + ".conf"),
-	}
-
-	for _, path := range paths {
-		data, err := os.ReadFile(path)
-		if err != nil {
-			continue
-		}
-
-		for line := range bytes.SplitSeq(data, []byte{'\n'}) {
-			if bytes.HasPrefix(line, []byte("Address = ")) {
-				address = string(bytes.TrimPrefix(line, []byte("Address = ")))
-			}
-			if bytes.HasPrefix(line, []byte("DNS = ")) {
-				dns = string(bytes.TrimPrefix(line, []byte("DNS = ")))
-			}
-			if bytes.HasPrefix(line, []byte("PersistentKeepalive = ")) {
-				alive = string(bytes.TrimPrefix(line, []byte("PersistentKeepalive = ")))
-			}
-			if bytes.HasPrefix(line, []byte("PresharedKey = ")) {
-				psk = string(bytes.TrimPrefix(line, []byte("PresharedKey = ")))
-			}
-		}
-	}
-
-	return address, dns, alive, psk
-}
-
-func parseKeys(interfaceName string) (string, string) {
-	var privKey, pubKey string
-
-	paths := []string{
-		filepath.Join("/etc/wireguard/" + interfaceName + ".conf"),
-		filepath.Join("/usr/local/etc/wireguard/" + interfaceName + ".conf"),
-	}
-
-	for _, path := range paths {
-		data, err := os.ReadFile(path)
-		if err != nil {
-			continue
-		}
-
-		for line := range bytes.SplitSeq(data, []byte{'\n'}) {
-			if bytes.HasPrefix(line, []byte("PrivateKey = ")) {
-				privKey = string(bytes.TrimPrefix(line, []byte("PrivateKey = ")))
-			}
-			if bytes.HasPrefix(line, []byte("PublicKey = ")) {
-				pubKey = string(bytes.TrimPrefix(line, []byte("PublicKey = ")))
-			}
-		}
-	}
-
-	if privKey != "" && pubKey != "" {
-		convert, err := wgtypes.ParseKey(privKey)
-		if err != nil {
-			return "", ""
-		}
-
-		return convert.PublicKey().String(), pubKey
-	}
-
-	return "", ""
-}
-
 func parseTime(handshake time.Time) string {
 	if handshake.IsZero() {
 		return "never"